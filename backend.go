@@ -0,0 +1,30 @@
+package main
+
+// AudioBackend abstracts the input audio source so the visualizer's
+// metering and rendering logic doesn't depend on which one is wired up.
+// Start must register channels input channels and invoke onFrames once per
+// audio block for as long as the backend is running; onFrames is called
+// from the backend's realtime thread and must not block.
+type AudioBackend interface {
+	Start(channels int, onFrames func(frames [][]float32)) error
+	// OnShutdown registers callback to run if the backend stops on its own
+	// (e.g. the JACK server exits) rather than via Close, so the caller can
+	// still restore the terminal and unblock waiting on an interrupt.
+	// Backends with no equivalent notification may no-op.
+	OnShutdown(callback func())
+	SampleRate() int
+	BufferSize() int
+	Close()
+}
+
+// MIDIBackend is implemented by backends that can also supply MIDI input,
+// auto-connecting from system:midi_capture_* the same way AudioBackend
+// connects audio ports to system:monitor_*. -midi requires a backend that
+// implements this; portaudio doesn't.
+type MIDIBackend interface {
+	// StartMIDI registers channels MIDI input ports. onEvent is invoked once
+	// per decoded event; onTick is invoked once per process callback, after
+	// that callback's events, for periodic work like velocity decay.
+	// Neither may block.
+	StartMIDI(channels int, onEvent func(channel int, status, data1, data2 byte), onTick func()) error
+}
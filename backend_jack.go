@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/xthexder/go-jack"
+)
+
+// jackBackend implements AudioBackend using a JACK client, auto-connecting
+// its input ports to system:monitor_* the same way the tool always has.
+type jackBackend struct {
+	client   *jack.Client
+	portsIn  []*jack.Port
+	onFrames func(frames [][]float32)
+	frames   [][]float32
+
+	portsMIDI []*jack.Port
+	midiEvent func(channel int, status, data1, data2 byte)
+	midiTick  func()
+
+	shutdownCallback func() // set via OnShutdown, possibly before a client exists
+}
+
+func (b *jackBackend) Start(channels int, onFrames func(frames [][]float32)) error {
+	var status int
+	var clientName string
+
+	for i := 0; i < 1000; i++ {
+		clientName = fmt.Sprintf("spectrum analyser %d", i)
+		b.client, status = jack.ClientOpen(clientName, jack.NoStartServer)
+		if status == 0 {
+			break
+		}
+	}
+	if status != 0 {
+		return fmt.Errorf("failed to initialize client, errcode: %d", status)
+	}
+	if b.shutdownCallback != nil {
+		b.client.OnShutdown(b.shutdownCallback)
+	}
+
+	b.onFrames = onFrames
+	b.frames = make([][]float32, channels)
+
+	if code := b.client.SetProcessCallback(b.process); code != 0 {
+		return fmt.Errorf("failed to set process callback: %d", code)
+	}
+
+	if code := b.client.Activate(); code != 0 {
+		return fmt.Errorf("failed to activate client: %d", code)
+	}
+
+	// registering audio channel inputs and connecting them automatically to system monitor output
+	for i := 1; i <= channels; i++ {
+		portName := fmt.Sprintf("input_%d", i)
+		port := b.client.PortRegister(portName, jack.DEFAULT_AUDIO_TYPE, jack.PortIsInput, 0)
+		b.portsIn = append(b.portsIn, port)
+
+		srcPortName := fmt.Sprintf("system:monitor_%d", i)
+		dstPortName := fmt.Sprintf("%s:input_%d", clientName, i)
+
+		code := b.client.Connect(srcPortName, dstPortName)
+		if code != 0 {
+			// fmt.Printf("Failed connecting port \"%s\" to\"%s\"\n", srcPortName, dstPortName)
+		}
+	}
+
+	return nil
+}
+
+// JACK callback
+func (b *jackBackend) process(nframes uint32) int {
+	for i, port := range b.portsIn {
+		samples := port.GetBuffer(nframes)
+
+		frame := make([]float32, len(samples))
+		for n, s := range samples {
+			frame[n] = float32(s)
+		}
+		b.frames[i] = frame
+	}
+	b.onFrames(b.frames)
+	return 0
+}
+
+// StartMIDI registers channels MIDI input ports on their own JACK client
+// (separate from Start's audio client, since -midi runs instead of the
+// audio visualizer), connecting each automatically to system:midi_capture_*
+// the same way Start connects audio inputs to system:monitor_*.
+func (b *jackBackend) StartMIDI(channels int, onEvent func(channel int, status, data1, data2 byte), onTick func()) error {
+	var status int
+	var clientName string
+
+	for i := 0; i < 1000; i++ {
+		clientName = fmt.Sprintf("spectrum analyser midi %d", i)
+		b.client, status = jack.ClientOpen(clientName, jack.NoStartServer)
+		if status == 0 {
+			break
+		}
+	}
+	if status != 0 {
+		return fmt.Errorf("failed to initialize midi client, errcode: %d", status)
+	}
+	if b.shutdownCallback != nil {
+		b.client.OnShutdown(b.shutdownCallback)
+	}
+
+	b.midiEvent = onEvent
+	b.midiTick = onTick
+
+	if code := b.client.SetProcessCallback(b.processMIDI); code != 0 {
+		return fmt.Errorf("failed to set midi process callback: %d", code)
+	}
+
+	if code := b.client.Activate(); code != 0 {
+		return fmt.Errorf("failed to activate midi client: %d", code)
+	}
+
+	// registering MIDI channel inputs and connecting them automatically to system midi capture ports
+	for i := 1; i <= channels; i++ {
+		portName := fmt.Sprintf("midi_in_%d", i)
+		port := b.client.PortRegister(portName, jack.DEFAULT_MIDI_TYPE, jack.PortIsInput, 0)
+		b.portsMIDI = append(b.portsMIDI, port)
+
+		srcPortName := fmt.Sprintf("system:midi_capture_%d", i)
+		dstPortName := fmt.Sprintf("%s:%s", clientName, portName)
+
+		code := b.client.Connect(srcPortName, dstPortName)
+		if code != 0 {
+			// fmt.Printf("Failed connecting port \"%s\" to\"%s\"\n", srcPortName, dstPortName)
+		}
+	}
+
+	return nil
+}
+
+// JACK callback for MIDI ports: decodes each event in nframes via
+// jack_midi_event_get (wrapped by GetMidiEvents) and hands status/data1/data2
+// off to midiEvent, then runs midiTick once the whole callback is drained.
+func (b *jackBackend) processMIDI(nframes uint32) int {
+	for i, port := range b.portsMIDI {
+		for _, ev := range port.GetMidiEvents(nframes) {
+			if len(ev.Buffer) == 0 {
+				continue
+			}
+			var data1, data2 byte
+			if len(ev.Buffer) > 1 {
+				data1 = ev.Buffer[1]
+			}
+			if len(ev.Buffer) > 2 {
+				data2 = ev.Buffer[2]
+			}
+			b.midiEvent(i, ev.Buffer[0], data1, data2)
+		}
+	}
+	b.midiTick()
+	return 0
+}
+
+// OnShutdown stores callback to run if the JACK server itself goes away,
+// independent of the SIGINT/SIGTERM path. AudioBackend.OnShutdown may be
+// called before Start/StartMIDI opens the client, so the callback is only
+// wired to the client once one exists, immediately after ClientOpen and
+// well before Activate.
+func (b *jackBackend) OnShutdown(callback func()) {
+	b.shutdownCallback = callback
+	if b.client != nil {
+		b.client.OnShutdown(callback)
+	}
+}
+
+func (b *jackBackend) SampleRate() int {
+	return int(b.client.GetSampleRate())
+}
+
+func (b *jackBackend) BufferSize() int {
+	return int(b.client.GetBufferSize())
+}
+
+func (b *jackBackend) Close() {
+	b.client.Close()
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/gordonklaus/portaudio"
+)
+
+// portaudioBufferSize is the fixed block size requested from PortAudio;
+// JACK callers instead size themselves to the server's own buffer.
+const portaudioBufferSize = 1024
+
+// portaudioBackend implements AudioBackend using PortAudio, so the meter
+// can run without a JACK server (e.g. on macOS/Windows, or a Linux box with
+// no JACK install).
+type portaudioBackend struct {
+	device string
+
+	stream     *portaudio.Stream
+	sampleRate int
+}
+
+func newPortaudioBackend(device string) *portaudioBackend {
+	return &portaudioBackend{device: device}
+}
+
+func (b *portaudioBackend) Start(channels int, onFrames func(frames [][]float32)) error {
+	if err := portaudio.Initialize(); err != nil {
+		return fmt.Errorf("failed to initialize portaudio: %w", err)
+	}
+
+	dev, err := b.resolveDevice()
+	if err != nil {
+		return err
+	}
+	b.sampleRate = int(dev.DefaultSampleRate)
+
+	params := portaudio.StreamParameters{
+		Input: portaudio.StreamDeviceParameters{
+			Device:   dev,
+			Channels: channels,
+			Latency:  dev.DefaultHighInputLatency,
+		},
+		SampleRate:      dev.DefaultSampleRate,
+		FramesPerBuffer: portaudioBufferSize,
+	}
+
+	callback := func(in []float32) {
+		frames := make([][]float32, channels)
+		for c := range frames {
+			frames[c] = make([]float32, len(in)/channels)
+		}
+		// de-interleave: in is [ch0, ch1, ..., ch0, ch1, ...]
+		for i, s := range in {
+			frames[i%channels][i/channels] = s
+		}
+		onFrames(frames)
+	}
+
+	stream, err := portaudio.OpenStream(params, callback)
+	if err != nil {
+		return fmt.Errorf("failed to open portaudio stream: %w", err)
+	}
+	b.stream = stream
+
+	return stream.Start()
+}
+
+func (b *portaudioBackend) resolveDevice() (*portaudio.DeviceInfo, error) {
+	if b.device == "" {
+		return portaudio.DefaultInputDevice()
+	}
+
+	devices, err := portaudio.Devices()
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range devices {
+		if d.Name == b.device && d.MaxInputChannels > 0 {
+			return d, nil
+		}
+	}
+	return nil, fmt.Errorf("no such input device: %q", b.device)
+}
+
+// OnShutdown is a no-op: PortAudio has no equivalent to JACK's
+// server-initiated shutdown notification.
+func (b *portaudioBackend) OnShutdown(callback func()) {}
+
+func (b *portaudioBackend) SampleRate() int {
+	return b.sampleRate
+}
+
+func (b *portaudioBackend) BufferSize() int {
+	return portaudioBufferSize
+}
+
+func (b *portaudioBackend) Close() {
+	b.stream.Stop()
+	b.stream.Close()
+	portaudio.Terminate()
+}
+
+// listPortaudioDevices prints every input-capable device's name and default
+// sample rate, for the -list-devices flag.
+func listPortaudioDevices() error {
+	if err := portaudio.Initialize(); err != nil {
+		return fmt.Errorf("failed to initialize portaudio: %w", err)
+	}
+	defer portaudio.Terminate()
+
+	devices, err := portaudio.Devices()
+	if err != nil {
+		return err
+	}
+
+	for _, d := range devices {
+		if d.MaxInputChannels == 0 {
+			continue
+		}
+		fmt.Printf("%s (%d ch, %.0f Hz)\n", d.Name, d.MaxInputChannels, d.DefaultSampleRate)
+	}
+	return nil
+}
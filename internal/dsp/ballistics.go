@@ -0,0 +1,73 @@
+package dsp
+
+import "math"
+
+// Ballistics integration modes for NewBallistics.
+const (
+	BallisticsDigital = "digital"
+	BallisticsPPM     = "ppm"
+	BallisticsVU      = "vu"
+)
+
+// Ballistics integrates instantaneous sample amplitude into a calibrated
+// meter reading using the single-pole attack/release filter
+// y = y + alpha*(x-y), with alpha derived from the requested mode's time
+// constants and the sample rate. PPM follows IEC 60268-10 Type IIa (10ms
+// attack to -1dB, 1.5s fallback from 0dB to -20dB); VU uses the classic
+// 300ms integration time for both attack and release.
+type Ballistics struct {
+	instant      bool // digital mode: track x exactly, bypassing the filter
+	attackAlpha  float64
+	releaseAlpha float64
+	value        float64
+}
+
+// NewBallistics builds a ballistics filter for mode, tuned to sampleRate.
+func NewBallistics(mode string, sampleRate int) *Ballistics {
+	switch mode {
+	case BallisticsPPM:
+		// time constants chosen so a full-scale step reaches -1dB after 10ms,
+		// and a 0dB reading falls to -20dB after 1.5s
+		return &Ballistics{
+			attackAlpha:  poleAlpha(0.010/math.Log(1/(1-math.Pow(10, -1.0/20))), sampleRate),
+			releaseAlpha: poleAlpha(1.5/math.Log(10), sampleRate),
+		}
+	case BallisticsVU:
+		alpha := poleAlpha(0.300, sampleRate)
+		return &Ballistics{attackAlpha: alpha, releaseAlpha: alpha}
+	default:
+		return &Ballistics{instant: true}
+	}
+}
+
+// poleAlpha derives a single-pole filter coefficient that reaches ~63% of a
+// step input after timeConstant seconds.
+func poleAlpha(timeConstant float64, sampleRate int) float64 {
+	return 1 - math.Exp(-1/(timeConstant*float64(sampleRate)))
+}
+
+// Add integrates one instantaneous sample amplitude and returns the updated
+// reading.
+func (b *Ballistics) Add(x float64) float64 {
+	if b.instant {
+		b.value = x
+		return b.value
+	}
+
+	alpha := b.releaseAlpha
+	if x > b.value {
+		alpha = b.attackAlpha
+	}
+	b.value += alpha * (x - b.value)
+	return b.value
+}
+
+// Value returns the current reading without integrating a new sample.
+func (b *Ballistics) Value() float64 {
+	return b.value
+}
+
+// Reset zeroes the filter state.
+func (b *Ballistics) Reset() {
+	b.value = 0
+}
@@ -0,0 +1,53 @@
+package dsp
+
+import "testing"
+
+func TestBallisticsDigitalTracksInstantly(t *testing.T) {
+	b := NewBallistics(BallisticsDigital, 48000)
+	if got := b.Add(0.5); got != 0.5 {
+		t.Fatalf("digital Add(0.5) = %v, want 0.5", got)
+	}
+	if got := b.Add(0.1); got != 0.1 {
+		t.Fatalf("digital Add(0.1) = %v, want 0.1", got)
+	}
+}
+
+func TestBallisticsPPMAttackApproachesTarget(t *testing.T) {
+	const sampleRate = 48000
+	b := NewBallistics(BallisticsPPM, sampleRate)
+
+	// drive a full-scale step for 10ms (the PPM attack time to -1dB)
+	for i := 0; i < sampleRate/100; i++ {
+		b.Add(1.0)
+	}
+
+	if v := b.Value(); v < 0.5 {
+		t.Fatalf("PPM value after the 10ms attack time = %v, want significant rise toward 1.0", v)
+	}
+}
+
+func TestPeakHoldLatchesThenDecays(t *testing.T) {
+	const sampleRate = 48000
+	p := NewPeakHold(sampleRate, 0.1, 20)
+
+	p.Add(1.0)
+	if v := p.Value(); v != 1.0 {
+		t.Fatalf("Value() after peak = %v, want 1.0", v)
+	}
+
+	// still within the hold window, lower samples shouldn't move it
+	for i := 0; i < sampleRate/200; i++ {
+		p.Add(0.1)
+	}
+	if v := p.Value(); v != 1.0 {
+		t.Fatalf("Value() during hold window = %v, want unchanged 1.0", v)
+	}
+
+	// well past the hold window, it should have decayed
+	for i := 0; i < sampleRate; i++ {
+		p.Add(0.0)
+	}
+	if v := p.Value(); v >= 1.0 {
+		t.Fatalf("Value() after hold+decay = %v, want < 1.0", v)
+	}
+}
@@ -0,0 +1,71 @@
+// Package dsp implements the signal-processing primitives backing the
+// spectrum-analyzer mode: an in-place FFT, window functions, and grouping
+// of FFT bins into logarithmically spaced frequency bands.
+package dsp
+
+import "math"
+
+// NextPowerOfTwo returns the smallest power of two that is >= n.
+func NextPowerOfTwo(n int) int {
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
+
+// HannWindow returns a Hann window of length n.
+func HannWindow(n int) []float64 {
+	w := make([]float64, n)
+	for i := range w {
+		w[i] = 0.5 * (1 - math.Cos(2*math.Pi*float64(i)/float64(n-1)))
+	}
+	return w
+}
+
+// FFT performs an in-place iterative radix-2 Cooley-Tukey FFT on data.
+// len(data) must be a power of two.
+func FFT(data []complex64) {
+	n := len(data)
+
+	// bit-reversal permutation
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j &^= bit
+		}
+		j |= bit
+		if i < j {
+			data[i], data[j] = data[j], data[i]
+		}
+	}
+
+	// butterflies, one stage per power of two up to log2(n)
+	for size := 2; size <= n; size <<= 1 {
+		half := size / 2
+		angle := -2 * math.Pi / float64(size)
+		wn := complex64(complex(math.Cos(angle), math.Sin(angle)))
+		for start := 0; start < n; start += size {
+			w := complex64(complex(1, 0))
+			for k := 0; k < half; k++ {
+				even := data[start+k]
+				odd := data[start+k+half] * w
+				data[start+k] = even + odd
+				data[start+k+half] = even - odd
+				w *= wn
+			}
+		}
+	}
+}
+
+// Magnitudes returns the normalized magnitude |X(k)|/N for each bin of an
+// FFT result produced by FFT.
+func Magnitudes(data []complex64) []float64 {
+	n := len(data)
+	mags := make([]float64, n)
+	for i, c := range data {
+		re, im := float64(real(c)), float64(imag(c))
+		mags[i] = math.Sqrt(re*re+im*im) / float64(n)
+	}
+	return mags
+}
@@ -0,0 +1,72 @@
+package dsp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNextPowerOfTwo(t *testing.T) {
+	cases := map[int]int{1: 1, 2: 2, 3: 4, 513: 1024, 1024: 1024}
+	for in, want := range cases {
+		if got := NextPowerOfTwo(in); got != want {
+			t.Errorf("NextPowerOfTwo(%d) = %d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestFFTSineBin(t *testing.T) {
+	const (
+		n          = 1024
+		sampleRate = 48000
+		binIndex   = 40 // bin under test
+	)
+	freq := float64(binIndex) * sampleRate / n
+
+	samples := make([]complex64, n)
+	for i := range samples {
+		samples[i] = complex64(complex(math.Sin(2*math.Pi*freq*float64(i)/sampleRate), 0))
+	}
+
+	FFT(samples)
+	mags := Magnitudes(samples)
+
+	peakBin := 0
+	for i, m := range mags[:n/2] {
+		if m > mags[peakBin] {
+			peakBin = i
+		}
+		_ = m
+	}
+
+	if peakBin != binIndex {
+		t.Fatalf("peak magnitude at bin %d, want %d", peakBin, binIndex)
+	}
+}
+
+func TestSpectrumAnalyzeSine(t *testing.T) {
+	const (
+		fftSize    = 1024
+		sampleRate = 48000
+	)
+	freq := 1000.0 // 1 kHz test tone, within the 30Hz..sampleRate/2 band range
+
+	samples := make([]float32, fftSize)
+	for i := range samples {
+		samples[i] = float32(math.Sin(2 * math.Pi * freq * float64(i) / sampleRate))
+	}
+
+	s := NewSpectrum(fftSize, sampleRate, 64, 30)
+	bands := s.Analyze(samples, -60)
+
+	peakBand := 0
+	for i, db := range bands {
+		if db > bands[peakBand] {
+			peakBand = i
+		}
+	}
+
+	// the 1kHz band should be well above the noise floor
+	if bands[peakBand] < -40 {
+		t.Fatalf("expected a band near 1kHz to stand out, strongest band %d = %.1fdB", peakBand, bands[peakBand])
+	}
+}
@@ -0,0 +1,129 @@
+package dsp
+
+import "math"
+
+// Biquad is a Direct-Form I IIR biquad filter: y = b0*x + b1*x1 + b2*x2 -
+// a1*y1 - a2*y2.
+type Biquad struct {
+	B0, B1, B2 float64
+	A1, A2     float64
+
+	x1, x2 float64
+	y1, y2 float64
+}
+
+// Process filters one sample and updates the filter state.
+func (b *Biquad) Process(x float64) float64 {
+	y := b.B0*x + b.B1*b.x1 + b.B2*b.x2 - b.A1*b.y1 - b.A2*b.y2
+	b.x2, b.x1 = b.x1, x
+	b.y2, b.y1 = b.y1, y
+	return y
+}
+
+// KWeightingFilter is the two-stage K-weighting pre-filter chain defined by
+// ITU-R BS.1770: a high-shelf "pre-filter" followed by a high-pass (RLB)
+// stage. Coefficients are re-derived for sampleRate at construction time,
+// so the same filter shape applies regardless of the JACK server's rate.
+type KWeightingFilter struct {
+	preFilter Biquad
+	highPass  Biquad
+}
+
+// NewKWeightingFilter builds a K-weighting filter chain tuned for sampleRate.
+func NewKWeightingFilter(sampleRate int) *KWeightingFilter {
+	fs := float64(sampleRate)
+
+	// high-shelf pre-filter, ~+4dB above ~1.7kHz
+	f0, g, q := 1681.9744509555319, 3.99984385397, 0.7071752369554193
+	k := math.Tan(math.Pi * f0 / fs)
+	vh := math.Pow(10, g/20)
+	vb := math.Pow(vh, 0.4996667741545416)
+	a0 := 1 + k/q + k*k
+	pre := Biquad{
+		B0: (vh + vb*k/q + k*k) / a0,
+		B1: 2 * (k*k - vh) / a0,
+		B2: (vh - vb*k/q + k*k) / a0,
+		A1: 2 * (k*k - 1) / a0,
+		A2: (1 - k/q + k*k) / a0,
+	}
+
+	// RLB high-pass, ~38Hz corner
+	f0, q = 38.13547087613982, 0.5003270373238773
+	k = math.Tan(math.Pi * f0 / fs)
+	a0 = 1 + k/q + k*k
+	hp := Biquad{
+		B0: 1,
+		B1: -2,
+		B2: 1,
+		A1: 2 * (k*k - 1) / a0,
+		A2: (1 - k/q + k*k) / a0,
+	}
+
+	return &KWeightingFilter{preFilter: pre, highPass: hp}
+}
+
+// Process runs one sample through the pre-filter then the high-pass stage.
+func (f *KWeightingFilter) Process(x float64) float64 {
+	return f.highPass.Process(f.preFilter.Process(x))
+}
+
+// LoudnessMeter tracks K-weighted mean-square energy for a single channel
+// over the 400ms momentary and 3s short-term windows defined by EBU R128.
+type LoudnessMeter struct {
+	filter *KWeightingFilter
+
+	momentary []float64
+	shortTerm []float64
+	momPos    int
+	stPos     int
+	momSum    float64
+	stSum     float64
+}
+
+// NewLoudnessMeter builds a loudness meter sized from sampleRate.
+func NewLoudnessMeter(sampleRate int) *LoudnessMeter {
+	return &LoudnessMeter{
+		filter:    NewKWeightingFilter(sampleRate),
+		momentary: make([]float64, sampleRate*400/1000),
+		shortTerm: make([]float64, sampleRate*3),
+	}
+}
+
+// Add K-weights and accumulates one sample into both windows.
+func (m *LoudnessMeter) Add(x float64) {
+	sq := m.filter.Process(x)
+	sq *= sq
+
+	m.momSum -= m.momentary[m.momPos]
+	m.momentary[m.momPos] = sq
+	m.momSum += sq
+	m.momPos = (m.momPos + 1) % len(m.momentary)
+
+	m.stSum -= m.shortTerm[m.stPos]
+	m.shortTerm[m.stPos] = sq
+	m.stSum += sq
+	m.stPos = (m.stPos + 1) % len(m.shortTerm)
+}
+
+// MomentaryMeanSquare returns the mean square over the last 400ms.
+func (m *LoudnessMeter) MomentaryMeanSquare() float64 {
+	return m.momSum / float64(len(m.momentary))
+}
+
+// ShortTermMeanSquare returns the mean square over the last 3s.
+func (m *LoudnessMeter) ShortTermMeanSquare() float64 {
+	return m.stSum / float64(len(m.shortTerm))
+}
+
+// Loudness computes LUFS from per-channel mean-square values and their
+// ITU-R BS.1770 channel weights (1.0 for L/R, 1.41 for surround channels).
+func Loudness(meanSquares, weights []float64) float64 {
+	var sum float64
+	for i, ms := range meanSquares {
+		sum += weights[i] * ms
+	}
+	if sum <= 0 {
+		return math.Inf(-1)
+	}
+	return -0.691 + 10*math.Log10(sum)
+}
@@ -0,0 +1,42 @@
+package dsp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLoudnessMeterSilence(t *testing.T) {
+	m := NewLoudnessMeter(48000)
+	for i := 0; i < 48000; i++ {
+		m.Add(0)
+	}
+
+	if ms := m.MomentaryMeanSquare(); ms != 0 {
+		t.Fatalf("silence momentary mean square = %v, want 0", ms)
+	}
+	if ms := m.ShortTermMeanSquare(); ms != 0 {
+		t.Fatalf("silence short-term mean square = %v, want 0", ms)
+	}
+}
+
+func TestLoudnessNegInfForSilentMix(t *testing.T) {
+	if got := Loudness([]float64{0, 0}, []float64{1.0, 1.0}); got != math.Inf(-1) {
+		t.Fatalf("Loudness of silence = %v, want -Inf", got)
+	}
+}
+
+func TestTruePeakDetectsFullScale(t *testing.T) {
+	tp := NewTruePeak()
+	samples := make([]float32, 256)
+	for i := range samples {
+		if i%2 == 0 {
+			samples[i] = 1
+		} else {
+			samples[i] = -1
+		}
+	}
+
+	if peak := tp.Detect(samples); peak < 0.5 {
+		t.Fatalf("Detect() = %v, want a peak near full scale", peak)
+	}
+}
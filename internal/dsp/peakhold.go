@@ -0,0 +1,47 @@
+package dsp
+
+import "math"
+
+// PeakHold latches the maximum amplitude seen, holds it for holdSeconds,
+// then decays it at decayDBPerSec until a new, higher peak arrives.
+type PeakHold struct {
+	holdSamples    int
+	decayPerSample float64
+
+	value float64
+	timer int
+}
+
+// NewPeakHold builds a peak-hold tracker sized from sampleRate.
+func NewPeakHold(sampleRate int, holdSeconds, decayDBPerSec float64) *PeakHold {
+	return &PeakHold{
+		holdSamples:    int(holdSeconds * float64(sampleRate)),
+		decayPerSample: math.Pow(10, -decayDBPerSec/20/float64(sampleRate)),
+	}
+}
+
+// Add integrates one instantaneous sample amplitude and returns the updated
+// held value.
+func (p *PeakHold) Add(x float64) float64 {
+	switch {
+	case x >= p.value:
+		p.value = x
+		p.timer = p.holdSamples
+	case p.timer > 0:
+		p.timer--
+	default:
+		p.value *= p.decayPerSample
+	}
+	return p.value
+}
+
+// Value returns the current held value without integrating a new sample.
+func (p *PeakHold) Value() float64 {
+	return p.value
+}
+
+// Reset zeroes the held value and hold timer.
+func (p *PeakHold) Reset() {
+	p.value = 0
+	p.timer = 0
+}
@@ -0,0 +1,84 @@
+package dsp
+
+import "math"
+
+// Spectrum groups the bins of an FFTSize-point FFT into Bands logarithmically
+// spaced bands between MinHz and SampleRate/2, one band per terminal column.
+type Spectrum struct {
+	FFTSize    int
+	SampleRate int
+	Bands      int
+	MinHz      float64
+
+	bandEdges []int
+	window    []float64
+	scratch   []complex64
+}
+
+// NewSpectrum builds a Spectrum analyzer for the given FFT size, sample rate
+// and number of output bands.
+func NewSpectrum(fftSize, sampleRate, bands int, minHz float64) *Spectrum {
+	maxHz := float64(sampleRate) / 2
+	logMin := math.Log2(minHz)
+	logMax := math.Log2(maxHz)
+
+	edges := make([]int, bands+1)
+	for i := 0; i <= bands; i++ {
+		frac := float64(i) / float64(bands)
+		hz := math.Exp2(logMin + frac*(logMax-logMin))
+		bin := int(hz * float64(fftSize) / float64(sampleRate))
+		if bin < 0 {
+			bin = 0
+		}
+		if bin > fftSize/2 {
+			bin = fftSize / 2
+		}
+		edges[i] = bin
+	}
+
+	return &Spectrum{
+		FFTSize:    fftSize,
+		SampleRate: sampleRate,
+		Bands:      bands,
+		MinHz:      minHz,
+		bandEdges:  edges,
+		window:     HannWindow(fftSize),
+		scratch:    make([]complex64, fftSize),
+	}
+}
+
+// Analyze windows samples (which must be FFTSize long), runs the FFT, and
+// returns one dB value per band, floored at floorDB.
+func (s *Spectrum) Analyze(samples []float32, floorDB float64) []float64 {
+	for i, sample := range samples {
+		s.scratch[i] = complex64(complex(float64(sample)*s.window[i], 0))
+	}
+
+	FFT(s.scratch)
+	mags := Magnitudes(s.scratch)
+
+	bands := make([]float64, s.Bands)
+	for b := 0; b < s.Bands; b++ {
+		lo, hi := s.bandEdges[b], s.bandEdges[b+1]
+		if hi <= lo {
+			hi = lo + 1
+		}
+
+		var peak float64
+		for _, m := range mags[lo:hi] {
+			if m > peak {
+				peak = m
+			}
+		}
+
+		db := floorDB
+		if peak > 0 {
+			db = 20 * math.Log10(peak)
+			if db < floorDB {
+				db = floorDB
+			}
+		}
+		bands[b] = db
+	}
+	return bands
+}
@@ -0,0 +1,78 @@
+package dsp
+
+import "math"
+
+const truePeakTapsPerPhase = 8
+
+// TruePeak estimates inter-sample ("true") peak amplitude by running the
+// signal through a 4x oversampling polyphase windowed-sinc FIR before
+// taking the peak, approximating the oversampling step of ITU-R BS.1770
+// true-peak metering.
+type TruePeak struct {
+	factor  int
+	phases  [][]float64 // per-phase FIR taps
+	history []float64   // tail of the previous block, for filter continuity
+}
+
+// NewTruePeak builds a 4x oversampling true-peak detector.
+func NewTruePeak() *TruePeak {
+	const factor = 4
+	taps := truePeakTapsPerPhase * factor
+
+	// windowed-sinc low-pass prototype at Fc = 1/(2*factor), Hann windowed
+	proto := make([]float64, taps)
+	center := float64(taps-1) / 2
+	for i := range proto {
+		x := float64(i) - center
+		sinc := 1.0
+		if x != 0 {
+			arg := math.Pi * x / float64(factor)
+			sinc = math.Sin(arg) / arg
+		}
+		window := 0.5 * (1 - math.Cos(2*math.Pi*float64(i)/float64(taps-1)))
+		proto[i] = sinc * window
+	}
+
+	phases := make([][]float64, factor)
+	for p := 0; p < factor; p++ {
+		for i := p; i < taps; i += factor {
+			phases[p] = append(phases[p], proto[i])
+		}
+	}
+
+	return &TruePeak{factor: factor, phases: phases}
+}
+
+// Detect returns the estimated true-peak amplitude across samples.
+func (t *TruePeak) Detect(samples []float32) float32 {
+	buf := make([]float64, 0, len(t.history)+len(samples))
+	buf = append(buf, t.history...)
+	for _, s := range samples {
+		buf = append(buf, float64(s))
+	}
+
+	tapLen := len(t.phases[0])
+	var peak float64
+	for _, taps := range t.phases {
+		for n := tapLen; n <= len(buf); n++ {
+			var acc float64
+			for k, c := range taps {
+				acc += c * buf[n-1-k]
+			}
+			if acc < 0 {
+				acc = -acc
+			}
+			if acc > peak {
+				peak = acc
+			}
+		}
+	}
+
+	if tapLen-1 <= len(buf) {
+		t.history = append([]float64(nil), buf[len(buf)-(tapLen-1):]...)
+	} else {
+		t.history = buf
+	}
+
+	return float32(peak)
+}
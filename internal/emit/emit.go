@@ -0,0 +1,55 @@
+// Package emit streams meter readings to a remote listener (a web dashboard,
+// a stage monitor, an OBS overlay) over the network, decoupled from the
+// terminal rendering in the main package.
+package emit
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// Frame is one sample of meter state across all channels, captured at the
+// moment a backend callback finished processing a block.
+type Frame struct {
+	T  int64          `json:"t"`
+	Ch []ChannelFrame `json:"ch"`
+}
+
+// ChannelFrame holds one channel's instantaneous meter readings. Fields a
+// caller isn't tracking (e.g. LUFS when meter=peak) are left at zero.
+type ChannelFrame struct {
+	Peak float32 `json:"peak"`
+	RMS  float32 `json:"rms"`
+	LUFS float32 `json:"lufs"`
+}
+
+// frameQueueSize bounds the buffer between the audio callback and an
+// emitter's send goroutine; once full, Send drops frames instead of
+// blocking the realtime thread.
+const frameQueueSize = 64
+
+// Emitter streams Frames to a remote listener without blocking the caller.
+type Emitter interface {
+	// Send enqueues frame for delivery. It never blocks: a full queue (a
+	// stalled client) drops the frame rather than stalling the caller.
+	Send(frame Frame)
+	Close() error
+}
+
+// New builds the Emitter described by target, a URL of the form
+// ws://host:port/path or osc://host:port/path.
+func New(target string) (Emitter, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -emit target %q: %w", target, err)
+	}
+
+	switch u.Scheme {
+	case "ws":
+		return newWSEmitter(u)
+	case "osc":
+		return newOSCEmitter(u)
+	default:
+		return nil, fmt.Errorf("unknown -emit scheme %q, must be one of: ws, osc", u.Scheme)
+	}
+}
@@ -0,0 +1,48 @@
+package emit
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+
+	"github.com/hypebeast/go-osc/osc"
+)
+
+// oscEmitter sends one /meter/<ch>/peak message per channel at each tick to
+// a fixed OSC listener.
+type oscEmitter struct {
+	*ticker
+
+	client *osc.Client
+}
+
+func newOSCEmitter(u *url.URL) (*oscEmitter, error) {
+	host, portStr, err := net.SplitHostPort(u.Host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -emit osc target %q: %w", u.Host, err)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -emit osc port %q: %w", portStr, err)
+	}
+
+	e := &oscEmitter{client: osc.NewClient(host, port)}
+	e.ticker = newTicker(e.send)
+	return e, nil
+}
+
+func (e *oscEmitter) send(frame Frame) {
+	for i, ch := range frame.Ch {
+		msg := osc.NewMessage(fmt.Sprintf("/meter/%d/peak", i))
+		msg.Append(ch.Peak)
+		if err := e.client.Send(msg); err != nil {
+			return
+		}
+	}
+}
+
+func (e *oscEmitter) Close() error {
+	e.ticker.Close()
+	return nil
+}
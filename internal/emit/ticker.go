@@ -0,0 +1,62 @@
+package emit
+
+import "time"
+
+// emitHz is the cadence frames are flushed to a remote listener at,
+// independent of how fast the audio callback produces them (e.g. 48kHz/64
+// frames would otherwise be ~750 frames/sec).
+const emitHz = 30
+
+// ticker buffers Frames from the audio callback and flushes the most recent
+// one to send at a fixed cadence. Send never blocks: a full queue (a
+// stalled client) drops the frame.
+type ticker struct {
+	frames chan Frame
+	stop   chan struct{}
+}
+
+// newTicker starts a goroutine that calls send at emitHz with the latest
+// Frame queued since the previous tick.
+func newTicker(send func(Frame)) *ticker {
+	t := &ticker{
+		frames: make(chan Frame, frameQueueSize),
+		stop:   make(chan struct{}),
+	}
+	go t.run(send)
+	return t
+}
+
+func (t *ticker) run(send func(Frame)) {
+	tick := time.NewTicker(time.Second / emitHz)
+	defer tick.Stop()
+
+	var latest Frame
+	have := false
+	for {
+		select {
+		case f := <-t.frames:
+			latest = f
+			have = true
+		case <-tick.C:
+			if have {
+				send(latest)
+				have = false
+			}
+		case <-t.stop:
+			return
+		}
+	}
+}
+
+// Send enqueues frame for delivery on the next tick.
+func (t *ticker) Send(frame Frame) {
+	select {
+	case t.frames <- frame:
+	default:
+	}
+}
+
+// Close stops the ticker goroutine.
+func (t *ticker) Close() {
+	close(t.stop)
+}
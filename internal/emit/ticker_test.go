@@ -0,0 +1,66 @@
+package emit
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeSink collects Frames flushed by a ticker, standing in for a real
+// network emitter so the queueing/ordering contract can be tested without a
+// socket.
+type fakeSink struct {
+	*ticker
+	got chan Frame
+}
+
+func newFakeSink() *fakeSink {
+	s := &fakeSink{got: make(chan Frame, frameQueueSize)}
+	s.ticker = newTicker(func(f Frame) { s.got <- f })
+	return s
+}
+
+func TestTickerDeliversFramesInOrder(t *testing.T) {
+	s := newFakeSink()
+	defer s.Close()
+
+	for i := 0; i < 5; i++ {
+		s.Send(Frame{T: int64(i)})
+		time.Sleep(40 * time.Millisecond) // > one tick, so each Send lands in its own flush
+	}
+
+	for i := 0; i < 5; i++ {
+		select {
+		case f := <-s.got:
+			if f.T != int64(i) {
+				t.Fatalf("frame %d: got T=%d, want %d", i, f.T, i)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("frame %d: timed out waiting for delivery", i)
+		}
+	}
+}
+
+func TestTickerSendNeverBlocksUnderLoad(t *testing.T) {
+	s := newFakeSink()
+	defer s.Close()
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 1000; i++ {
+			s.Send(Frame{T: int64(i)})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Send blocked under load")
+	}
+
+	select {
+	case <-s.got:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for a delivered frame")
+	}
+}
@@ -0,0 +1,103 @@
+package emit
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// wsEmitter serves target's path over HTTP, upgrading each connecting
+// client to a websocket and streaming one JSON Frame per tick.
+type wsEmitter struct {
+	*ticker
+
+	server   *http.Server
+	upgrader websocket.Upgrader
+
+	mu      sync.Mutex
+	clients map[*websocket.Conn]bool
+}
+
+func newWSEmitter(u *url.URL) (*wsEmitter, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid -emit ws target %q: missing host:port", u.String())
+	}
+
+	e := &wsEmitter{clients: make(map[*websocket.Conn]bool)}
+	e.ticker = newTicker(e.broadcast)
+
+	path := u.Path
+	if path == "" {
+		path = "/"
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, e.handleWS)
+	e.server = &http.Server{Addr: u.Host, Handler: mux}
+
+	go func() {
+		if err := e.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("emit: websocket server stopped: %v", err)
+		}
+	}()
+
+	return e, nil
+}
+
+func (e *wsEmitter) handleWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := e.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	e.mu.Lock()
+	e.clients[conn] = true
+	e.mu.Unlock()
+
+	// Clients never send anything meaningful, but we still need to read so
+	// that close/ping control frames are processed and the connection is
+	// dropped from e.clients once the client goes away.
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				e.mu.Lock()
+				delete(e.clients, conn)
+				e.mu.Unlock()
+				conn.Close()
+				return
+			}
+		}
+	}()
+}
+
+func (e *wsEmitter) broadcast(frame Frame) {
+	payload, err := json.Marshal(frame)
+	if err != nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for conn := range e.clients {
+		if err := conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+			conn.Close()
+			delete(e.clients, conn)
+		}
+	}
+}
+
+func (e *wsEmitter) Close() error {
+	e.ticker.Close()
+
+	e.mu.Lock()
+	for conn := range e.clients {
+		conn.Close()
+	}
+	e.mu.Unlock()
+
+	return e.server.Close()
+}
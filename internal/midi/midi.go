@@ -0,0 +1,51 @@
+// Package midi decodes raw JACK MIDI events into per-channel state that can
+// be rendered independently of how the events arrived.
+package midi
+
+import "math"
+
+// State tracks one MIDI channel's note velocities, controller values, and
+// pitch bend.
+type State struct {
+	Notes     [128]float64 // 0..1 velocity, decaying once per callback
+	CC        [128]float64 // 0..1 controller value
+	PitchBend float64      // -1..1, centered at 0
+}
+
+// NewState returns a State with every value at rest.
+func NewState() *State {
+	return &State{}
+}
+
+// HandleEvent decodes one raw MIDI event (status/data1/data2, as delivered
+// by jack_midi_event_get) and updates the channel state. Only Note-On
+// (0x90), Control Change (0xB0) and Pitch Bend (0xE0) are recognized; a
+// Note-On with velocity 0 is a note-off per the MIDI spec, which falls out
+// naturally since it just sets the velocity to 0.
+func (s *State) HandleEvent(status, data1, data2 byte) {
+	switch status & 0xF0 {
+	case 0x90:
+		s.Notes[data1&0x7F] = float64(data2) / 127
+	case 0xB0:
+		s.CC[data1&0x7F] = float64(data2) / 127
+	case 0xE0:
+		raw := int(data1&0x7F) | int(data2&0x7F)<<7
+		s.PitchBend = float64(raw-8192) / 8192
+	}
+}
+
+// Decay multiplies every note velocity by factor (0..1), so a struck note
+// fades out visually instead of cutting off the instant it's released.
+func (s *State) Decay(factor float64) {
+	for i := range s.Notes {
+		s.Notes[i] *= factor
+	}
+}
+
+// DecayPerCallback derives the per-callback multiplier that fades a
+// velocity by decayDBPerSec decibels per second, given how many samples
+// each JACK callback covers.
+func DecayPerCallback(decayDBPerSec float64, sampleRate, bufferSize int) float64 {
+	callbacksPerSec := float64(sampleRate) / float64(bufferSize)
+	return math.Pow(10, -decayDBPerSec/20/callbacksPerSec)
+}
@@ -0,0 +1,45 @@
+package midi
+
+import "testing"
+
+func TestHandleEventNoteOnSetsVelocity(t *testing.T) {
+	s := NewState()
+	s.HandleEvent(0x90, 60, 100)
+	if got, want := s.Notes[60], 100.0/127; got != want {
+		t.Fatalf("Notes[60] = %v, want %v", got, want)
+	}
+}
+
+func TestHandleEventNoteOnZeroVelocityIsNoteOff(t *testing.T) {
+	s := NewState()
+	s.HandleEvent(0x90, 60, 100)
+	s.HandleEvent(0x90, 60, 0)
+	if s.Notes[60] != 0 {
+		t.Fatalf("Notes[60] = %v, want 0 after note-off", s.Notes[60])
+	}
+}
+
+func TestHandleEventCC(t *testing.T) {
+	s := NewState()
+	s.HandleEvent(0xB0, 7, 64)
+	if got, want := s.CC[7], 64.0/127; got != want {
+		t.Fatalf("CC[7] = %v, want %v", got, want)
+	}
+}
+
+func TestHandleEventPitchBendCentered(t *testing.T) {
+	s := NewState()
+	s.HandleEvent(0xE0, 0, 64) // 64<<7 == 8192, the spec's centered value
+	if s.PitchBend != 0 {
+		t.Fatalf("PitchBend = %v, want 0 centered", s.PitchBend)
+	}
+}
+
+func TestDecayFadesVelocity(t *testing.T) {
+	s := NewState()
+	s.Notes[10] = 1.0
+	s.Decay(0.5)
+	if s.Notes[10] != 0.5 {
+		t.Fatalf("Notes[10] after decay = %v, want 0.5", s.Notes[10])
+	}
+}
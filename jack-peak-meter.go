@@ -3,12 +3,31 @@ package main
 import (
 	"flag"
 	"fmt"
+	"math"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
+	"time"
 	"unsafe"
 
-	"github.com/xthexder/go-jack"
+	"github.com/mikedickey/jack-peak-meter/internal/dsp"
+	"github.com/mikedickey/jack-peak-meter/internal/emit"
+	"github.com/mikedickey/jack-peak-meter/internal/midi"
+)
+
+const (
+	spectrumFFTMin  = 1024  // minimum FFT size, rounded up to a power of two
+	spectrumMinHz   = 30.0  // lowest frequency covered by the band layout
+	spectrumFloorDB = -60.0 // dB value rendered as an empty column
+)
+
+const midiDecayDBPerSec = 20.0 // note velocity decay rate, applied once per MIDI callback
+
+const (
+	lufsFloor           = -60.0 // LUFS value mapped onto an empty bar
+	surroundChanWeight  = 1.41  // ITU-R BS.1770 weight for surround channels
+	truePeakColumnWidth = 11    // reserved width for the " TP:+dd.ddB" column
 )
 
 const (
@@ -29,105 +48,169 @@ type visualizer struct {
 	amplifer    float64 // Compensate weak audio signal with this ultimate amplifier value
 	printValues bool
 	printChnIdx bool
+	mode        string // "peak" (default) or "spectrum"
+	meter       string // "peak" (default), "rms", "lufs-m" or "lufs-s"
+	truePeak    bool   // additionally track/print oversampled true peak
+
+	ballisticsMode string  // "digital" (default), "ppm" or "vu"; only applies to meter "peak"
+	peakHoldTime   float64 // seconds a peak-hold marker latches before decaying
+	peakDecayRate  float64 // dB/s the peak-hold marker decays once released
 
 	additionalBuffer int
 	avg              float32
 	avgMain          []float32
 	lastValues       [][]float32
 
-	client  *jack.Client
-	PortsIn []*jack.Port
-}
+	ballistics []*dsp.Ballistics
+	peakHolds  []*dsp.PeakHold
 
-func (v *visualizer) Start() error {
-	var status int
-	var clientName string
-
-	// trying to establish JACK client
-	for i := 0; i < 1000; i++ {
-		clientName = fmt.Sprintf("spectrum analyser %d", i)
-		v.client, status = jack.ClientOpen(clientName, jack.NoStartServer)
-		if status == 0 {
-			break
-		}
-	}
-	if status != 0 {
-		return fmt.Errorf("failed to initialize client, errcode: %d", status)
-	}
-	defer v.client.Close()
+	ringBuffers [][]float32 // per-channel spectrum accumulation buffer
+	ringPos     []int
+	spectrums   []*dsp.Spectrum
 
-	// registering JACK callback
-	if code := v.client.SetProcessCallback(v.process); code != 0 {
-		return fmt.Errorf("failed to set process callback: %d", code)
-	}
-	v.client.OnShutdown(v.shutdown)
+	loudnessMeters    []*dsp.LoudnessMeter
+	channelWeights    []float64
+	truePeakDetectors []*dsp.TruePeak
+	truePeakValues    []float32
 
-	fmt.Print(disableCursor) // disablingCursorblink
-	fmt.Print("\n")
+	emitter emit.Emitter // optional network output of per-channel meter values
 
-	// Activating client
-	if code := v.client.Activate(); code != 0 {
-		return fmt.Errorf("failed to activate client: %d", code)
-	}
+	midiOn       bool   // run the MIDI visualizer instead of the audio one
+	midiChannels int    // amount of MIDI input ports/rows
+	midiView     string // "notes" (default, piano-roll) or "cc" (bar grid)
+	midiStates   []*midi.State
+	midiDecay    float64 // per-callback note velocity decay factor
 
-	// registering audio channels inputs and connecting them automatically to system monitor output
-	for i := 1; i <= v.channels; i++ {
-		portName := fmt.Sprintf("input_%d", i)
-		port := v.client.PortRegister(portName, jack.DEFAULT_AUDIO_TYPE, jack.PortIsInput, 0)
-		v.PortsIn = append(v.PortsIn, port)
+	backend AudioBackend
 
-		srcPortName := fmt.Sprintf("system:monitor_%d", i)
-		dstPortName := fmt.Sprintf("%s:input_%d", clientName, i)
+	shutdownOnce sync.Once // shutdown can run from either the signal handler or backend.OnShutdown
+}
 
-		code := v.client.Connect(srcPortName, dstPortName)
-		if code != 0 {
-			// fmt.Printf("Failed connecting port \"%s\" to\"%s\"\n", srcPortName, dstPortName)
-		}
+func (v *visualizer) Start() error {
+	interrupted := make(chan bool, 2)
+
+	// the backend can also stop on its own (e.g. the JACK server exits);
+	// register this before starting so a shutdown racing with Start is
+	// still observed, and make sure it still restores the terminal and
+	// unblocks us below
+	v.backend.OnShutdown(func() {
+		v.shutdownOnce.Do(v.shutdown)
+		interrupted <- true
+	})
+
+	var err error
+	if v.midiOn {
+		err = v.startMIDI()
+	} else {
+		err = v.startAudio()
+	}
+	if err != nil {
+		return err
 	}
 
-	interrupted := make(chan bool)
+	fmt.Print(disableCursor) // disablingCursorblink
+	fmt.Print("\n")
 
 	// signal handler
 	sigChan := make(chan os.Signal, 2)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 	go func() {
 		<-sigChan
-		v.shutdown()
+		v.shutdownOnce.Do(v.shutdown)
 		interrupted <- true
-
 	}()
 
-	buffer := int(v.client.GetBufferSize())
-	v.additionalBuffer = v.calculateAdditionalBuffer(buffer)
-
 	<-interrupted
 	return nil
 }
 
-func getHighestSpread(samples []jack.AudioSample) jack.AudioSample {
-	var winner jack.AudioSample
-	for _, s := range samples {
-		if s < 0 {
-			s = -s
-		}
+// startAudio wires up the backend's audio callback and the meters/spectrum
+// analyzers that mode/meter/truePeak/emitter need.
+func (v *visualizer) startAudio() error {
+	if err := v.backend.Start(v.channels, v.onFrames); err != nil {
+		return err
+	}
 
-		if s > winner {
-			winner = s
-		}
+	if v.mode == "spectrum" {
+		v.setupSpectrum(v.backend.SampleRate())
+	}
+	if v.meter != "peak" || v.truePeak || v.emitter != nil {
+		v.setupMeter(v.backend.SampleRate())
+	}
+	if v.meter == "peak" || v.emitter != nil {
+		v.setupBallistics(v.backend.SampleRate())
 	}
-	return winner
+	v.additionalBuffer = v.calculateAdditionalBuffer(v.backend.BufferSize())
+	return nil
+}
+
+// startMIDI wires up the backend's MIDI callbacks in place of the audio
+// visualizer. It requires a backend that implements MIDIBackend.
+func (v *visualizer) startMIDI() error {
+	mb, ok := v.backend.(MIDIBackend)
+	if !ok {
+		return fmt.Errorf("-midi requires a backend that supports MIDI input (e.g. jack)")
+	}
+
+	v.midiStates = make([]*midi.State, v.midiChannels)
+	for i := range v.midiStates {
+		v.midiStates[i] = midi.NewState()
+	}
+
+	if err := mb.StartMIDI(v.midiChannels, v.onMIDIEvent, v.onMIDITick); err != nil {
+		return err
+	}
+
+	v.midiDecay = midi.DecayPerCallback(midiDecayDBPerSec, v.backend.SampleRate(), v.backend.BufferSize())
+	v.additionalBuffer = v.calculateAdditionalBuffer(v.backend.BufferSize())
+	return nil
 }
 
-// JACK callback
-func (v *visualizer) process(nframes uint32) int {
+// onFrames is the backend callback: frames holds one []float32 per channel,
+// all the same length.
+func (v *visualizer) onFrames(frames [][]float32) {
+	if v.mode == "spectrum" {
+		v.onFramesSpectrum(frames)
+		return
+	}
+
 	counter += 1
-	for i, port := range v.PortsIn {
-		samples := port.GetBuffer(nframes)
 
-		highest := float32(getHighestSpread(samples))
-		highest *= float32(v.amplifer)
+	var emitFrame emit.Frame
+	if v.emitter != nil {
+		emitFrame = emit.Frame{T: time.Now().UnixNano(), Ch: make([]emit.ChannelFrame, v.channels)}
+	}
+
+	for i, samples := range frames {
+		var peak float32
+		if v.meter == "peak" || v.emitter != nil {
+			peak = v.peakValue(i, samples)
+		}
+		if v.loudnessMeters != nil {
+			for _, s := range samples {
+				v.loudnessMeters[i].Add(float64(s) * v.amplifer)
+			}
+		}
+
+		var value float32
+		if v.meter == "peak" {
+			value = peak
+		} else {
+			value = v.meterValue(i)
+		}
+		v.avgMain[i] += value
+
+		if v.truePeak {
+			amplified := make([]float32, len(samples))
+			for n, s := range samples {
+				amplified[n] = s * float32(v.amplifer)
+			}
+			v.truePeakValues[i] = v.truePeakDetectors[i].Detect(amplified)
+		}
 
-		v.avgMain[i] += highest
+		if v.emitter != nil {
+			emitFrame.Ch[i] = v.emitChannel(i, peak)
+		}
 
 		if counter >= v.additionalBuffer {
 			value := v.avgMain[i] / float32(v.additionalBuffer)
@@ -138,7 +221,14 @@ func (v *visualizer) process(nframes uint32) int {
 			if termHeight < v.channels {
 				fmt.Printf(">> Not sufficient space for bars <<\r")
 			} else {
-				v.printBar(v.getAvg(i), termWidth, i)
+				barWidth := termWidth
+				if v.truePeak {
+					barWidth -= truePeakColumnWidth
+				}
+				v.printBar(v.getAvg(i), barWidth, i)
+				if v.truePeak {
+					v.printTruePeak(v.truePeakValues[i])
+				}
 
 				if i+1 != v.channels { // do not print newline for last bar
 					fmt.Print("\n")
@@ -148,23 +238,221 @@ func (v *visualizer) process(nframes uint32) int {
 		}
 
 	}
+
+	if v.emitter != nil {
+		v.emitter.Send(emitFrame)
+	}
+
 	if counter >= v.additionalBuffer {
 		counter = 0
 		for i := 1; i < v.channels; i++ {
 			fmt.Print(moveCursorUp)
 		}
 	}
-
-	return 0
 }
 
-// JACK callback
 func (v *visualizer) shutdown() {
 	fmt.Print(enableCursor + "\n")
-	v.client.Close()
+	for _, b := range v.ballistics {
+		b.Reset()
+	}
+	for _, p := range v.peakHolds {
+		p.Reset()
+	}
+	if v.emitter != nil {
+		v.emitter.Close()
+	}
+	v.backend.Close()
+}
+
+// setupMeter allocates the per-channel loudness meters and/or true-peak
+// detectors needed by v.meter and v.truePeak, sized from the backend's
+// sample rate. Channel weights follow ITU-R BS.1770: the first two channels
+// (L/R) get weight 1.0, any further channel is treated as surround (1.41).
+func (v *visualizer) setupMeter(sampleRate int) {
+	v.channelWeights = make([]float64, v.channels)
+	for i := range v.channelWeights {
+		v.channelWeights[i] = 1.0
+		if i >= 2 {
+			v.channelWeights[i] = surroundChanWeight
+		}
+	}
+
+	if v.meter != "peak" {
+		v.loudnessMeters = make([]*dsp.LoudnessMeter, v.channels)
+		for i := range v.loudnessMeters {
+			v.loudnessMeters[i] = dsp.NewLoudnessMeter(sampleRate)
+		}
+	}
+
+	if v.truePeak {
+		v.truePeakDetectors = make([]*dsp.TruePeak, v.channels)
+		v.truePeakValues = make([]float32, v.channels)
+		for i := range v.truePeakDetectors {
+			v.truePeakDetectors[i] = dsp.NewTruePeak()
+		}
+	}
+}
+
+// meterValue returns channel i's current reading, normalized to [0, 1],
+// for v.meter.
+func (v *visualizer) meterValue(channel int) float32 {
+	m := v.loudnessMeters[channel]
+	weight := v.channelWeights[channel]
+
+	switch v.meter {
+	case "rms":
+		return clamp01(float32(math.Sqrt(m.MomentaryMeanSquare())))
+	case "lufs-m":
+		lufs := dsp.Loudness([]float64{m.MomentaryMeanSquare()}, []float64{weight})
+		return clamp01(float32((lufs - lufsFloor) / -lufsFloor))
+	case "lufs-s":
+		lufs := dsp.Loudness([]float64{m.ShortTermMeanSquare()}, []float64{weight})
+		return clamp01(float32((lufs - lufsFloor) / -lufsFloor))
+	default:
+		return 0
+	}
+}
+
+// emitChannel builds the emit.ChannelFrame for channel, reusing peak (the
+// block peak already computed by peakValue) and, when a loudness meter is
+// tracked, momentary RMS/LUFS. Fields the current flags don't track are left
+// at zero rather than the bar's clamped [0,1] display value. Silence (-Inf
+// LUFS, which JSON cannot encode) is reported as lufsFloor.
+func (v *visualizer) emitChannel(channel int, peak float32) emit.ChannelFrame {
+	cf := emit.ChannelFrame{Peak: peak}
+	if v.loudnessMeters != nil {
+		m := v.loudnessMeters[channel]
+		ms := m.MomentaryMeanSquare()
+		cf.RMS = float32(math.Sqrt(ms))
+		lufs := dsp.Loudness([]float64{ms}, []float64{v.channelWeights[channel]})
+		if math.IsInf(lufs, -1) {
+			lufs = lufsFloor
+		}
+		cf.LUFS = float32(lufs)
+	}
+	return cf
+}
+
+func clamp01(v float32) float32 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
+
+// printTruePeak prints the "TP:" dBFS column following a channel's bar.
+func (v *visualizer) printTruePeak(peak float32) {
+	db := float32(math.Inf(-1))
+	if peak > 0 {
+		db = float32(20 * math.Log10(float64(peak)))
+	}
+	fmt.Printf(" TP:%+5.1fdB", db)
+}
+
+// setupBallistics allocates the per-channel Ballistics and PeakHold trackers
+// used by the "peak" meter, sized from the backend's sample rate.
+func (v *visualizer) setupBallistics(sampleRate int) {
+	v.ballistics = make([]*dsp.Ballistics, v.channels)
+	v.peakHolds = make([]*dsp.PeakHold, v.channels)
+	for i := range v.ballistics {
+		v.ballistics[i] = dsp.NewBallistics(v.ballisticsMode, sampleRate)
+		v.peakHolds[i] = dsp.NewPeakHold(sampleRate, v.peakHoldTime, v.peakDecayRate)
+	}
 }
 
-func newVisualizer(channels, buffer int, amplifier float64, printValues, printChnIdx bool) visualizer {
+// peakValue runs channel i's samples through its ballistics filter and peak
+// hold tracker, returning the ballistics-integrated reading for the bar.
+// "digital" ballistics skip integration and report the block's raw peak, as
+// the instantaneous sample peak the mode is meant to show.
+func (v *visualizer) peakValue(channel int, samples []float32) float32 {
+	var blockPeak float32
+	for _, s := range samples {
+		as := float64(s) * v.amplifer
+		if as < 0 {
+			as = -as
+		}
+
+		if v.ballisticsMode != dsp.BallisticsDigital {
+			v.ballistics[channel].Add(as)
+		}
+		v.peakHolds[channel].Add(as)
+
+		if float32(as) > blockPeak {
+			blockPeak = float32(as)
+		}
+	}
+
+	if v.ballisticsMode == dsp.BallisticsDigital {
+		return blockPeak
+	}
+	return float32(v.ballistics[channel].Value())
+}
+
+// setupSpectrum allocates the per-channel ring buffers and dsp.Spectrum
+// analyzers used by spectrum mode, sized from the backend's sample rate.
+func (v *visualizer) setupSpectrum(sampleRate int) {
+	fftSize := dsp.NextPowerOfTwo(spectrumFFTMin)
+	termWidth, _ := getTermWidthHeight()
+	bands := termWidth
+	if bands < 1 {
+		bands = 1
+	}
+
+	v.ringBuffers = make([][]float32, v.channels)
+	v.ringPos = make([]int, v.channels)
+	v.spectrums = make([]*dsp.Spectrum, v.channels)
+	for i := 0; i < v.channels; i++ {
+		v.ringBuffers[i] = make([]float32, fftSize)
+		v.spectrums[i] = dsp.NewSpectrum(fftSize, sampleRate, bands, spectrumMinHz)
+	}
+}
+
+// onFramesSpectrum is the backend callback used in spectrum mode.
+func (v *visualizer) onFramesSpectrum(frames [][]float32) {
+	termWidth, termHeight := getTermWidthHeight()
+	if termHeight < v.channels {
+		fmt.Printf(">> Not sufficient space for bars <<\r")
+		return
+	}
+
+	printed := false
+	for i, samples := range frames {
+		ring := v.ringBuffers[i]
+		pos := v.ringPos[i]
+		for _, s := range samples {
+			ring[pos] = s
+			pos++
+			if pos >= len(ring) {
+				pos = 0
+
+				windowed := make([]float32, len(ring))
+				for n, rs := range ring {
+					windowed[n] = rs * float32(v.amplifer)
+				}
+				bands := v.spectrums[i].Analyze(windowed, spectrumFloorDB)
+				v.printSpectrum(bands, termWidth, i)
+
+				if i+1 != v.channels { // do not print newline for last bar
+					fmt.Print("\n")
+				}
+				printed = true
+			}
+		}
+		v.ringPos[i] = pos
+	}
+
+	if printed {
+		for i := 1; i < v.channels; i++ {
+			fmt.Print(moveCursorUp)
+		}
+	}
+}
+
+func newVisualizer(channels, buffer int, amplifier float64, printValues, printChnIdx bool, mode, meter string, truePeak bool, ballisticsMode string, peakHoldTime, peakDecayRate float64, emitter emit.Emitter, midiOn bool, midiChannels int, midiView string, backend AudioBackend) visualizer {
 	var lastValues [][]float32
 	var avgMin []float32
 
@@ -180,17 +468,25 @@ func newVisualizer(channels, buffer int, amplifier float64, printValues, printCh
 	}
 
 	return visualizer{
-		channels,
-		buffer,
-		amplifier,
-		printValues,
-		printChnIdx,
-		1,
-		0.0,
-		avgMin,
-		lastValues,
-		nil,
-		[]*jack.Port{},
+		channels:         channels,
+		buffer:           buffer,
+		amplifer:         amplifier,
+		printValues:      printValues,
+		printChnIdx:      printChnIdx,
+		mode:             mode,
+		meter:            meter,
+		truePeak:         truePeak,
+		ballisticsMode:   ballisticsMode,
+		peakHoldTime:     peakHoldTime,
+		peakDecayRate:    peakDecayRate,
+		additionalBuffer: 1,
+		avgMain:          avgMin,
+		lastValues:       lastValues,
+		emitter:          emitter,
+		midiOn:           midiOn,
+		midiChannels:     midiChannels,
+		midiView:         midiView,
+		backend:          backend,
 	}
 }
 
@@ -221,6 +517,8 @@ func (v *visualizer) calculateAdditionalBuffer(frameSize int) int {
 	return 512 / frameSize
 }
 
+const peakHoldChar = "▐"
+
 func (v *visualizer) printBar(value float32, width, chanNumber int) {
 	var bar = ""
 	if v.printValues {
@@ -238,23 +536,145 @@ func (v *visualizer) printBar(value float32, width, chanNumber int) {
 
 	bar = "\r" + bar
 
+	cells := make([]string, width)
 	fullBlocks := int(float32(width) * value)
-	for i := 0; i < fullBlocks; i++ {
-		bar += fillBlocks[8] // full block fill
+	for i := 0; i < width; i++ {
+		switch {
+		case i < fullBlocks:
+			cells[i] = fillBlocks[8] // full block fill
+		case i == fullBlocks:
+			fillBlockIdx := int((float32(width)*value - float32(fullBlocks)) * 8)
+			cells[i] = fillBlocks[fillBlockIdx] // transition block fill
+		default:
+			cells[i] = fillBlocks[0] // empty block fill
+		}
 	}
 
-	if fullBlocks < width {
-		fillBlockIdx := int((float32(width)*value - float32(fullBlocks)) * 8)
-		bar += fillBlocks[fillBlockIdx] // transition block fill
+	if v.peakHolds != nil {
+		holdValue := clamp01(float32(v.peakHolds[chanNumber].Value()))
+		holdCol := int(float32(width) * holdValue)
+		if holdCol >= 0 && holdCol < width {
+			cells[holdCol] = peakHoldChar
+		}
 	}
 
-	for i := 0; i <= width-fullBlocks-2; i++ {
-		bar += fillBlocks[0] // empty block fill
+	for _, cell := range cells {
+		bar += cell
 	}
 
 	fmt.Print(bar + "| ")
 }
 
+// printSpectrum renders one dB-scaled band per terminal column, using the
+// same fillBlocks ramp printBar uses for its horizontal peak bar.
+func (v *visualizer) printSpectrum(bands []float64, width, chanNumber int) {
+	bar := "\r"
+	if v.printChnIdx {
+		bar += fmt.Sprintf(" %2d:", chanNumber)
+	}
+
+	span := -spectrumFloorDB // dB range mapped onto the ramp, e.g. 60
+	for _, db := range bands {
+		if width <= 0 {
+			break
+		}
+		width--
+
+		normalized := (db - spectrumFloorDB) / span
+		if normalized < 0 {
+			normalized = 0
+		} else if normalized > 1 {
+			normalized = 1
+		}
+
+		idx := int(normalized * float64(len(fillBlocks)-1))
+		bar += fillBlocks[idx]
+	}
+
+	fmt.Print(bar + " ")
+}
+
+// onMIDIEvent is the MIDI backend callback, decoding one raw status/data
+// event for channel into that channel's running State.
+func (v *visualizer) onMIDIEvent(channel int, status, data1, data2 byte) {
+	if channel < 0 || channel >= len(v.midiStates) {
+		return
+	}
+	v.midiStates[channel].HandleEvent(status, data1, data2)
+}
+
+// onMIDITick is the MIDI backend's once-per-callback hook: it decays every
+// channel's note velocities, then redraws the piano-roll or CC grid at the
+// same throttled cadence onFrames uses for the audio bars.
+func (v *visualizer) onMIDITick() {
+	for _, s := range v.midiStates {
+		s.Decay(v.midiDecay)
+	}
+
+	counter += 1
+	if counter < v.additionalBuffer {
+		return
+	}
+	counter = 0
+
+	termWidth, termHeight := getTermWidthHeight()
+	if termHeight < v.midiChannels {
+		fmt.Printf(">> Not sufficient space for bars <<\r")
+		return
+	}
+
+	for i, s := range v.midiStates {
+		if v.midiView == "cc" {
+			v.printMIDIRow(s.CC[:], termWidth, i)
+		} else {
+			v.printMIDIRow(s.Notes[:], termWidth, i)
+		}
+		if i+1 != v.midiChannels { // do not print newline for last row
+			fmt.Print("\n")
+		}
+	}
+
+	for i := 1; i < v.midiChannels; i++ {
+		fmt.Print(moveCursorUp)
+	}
+}
+
+// printMIDIRow renders one row of 128 MIDI values (note velocities or CC
+// values) as a bar-grid: each terminal column covers a fixed span of MIDI
+// numbers, using the same fillBlocks ramp printBar/printSpectrum use,
+// brightness set by the loudest value within that span.
+func (v *visualizer) printMIDIRow(values []float64, width, chanNumber int) {
+	bar := "\r"
+	if v.printChnIdx {
+		bar += fmt.Sprintf(" %2d:", chanNumber)
+		width -= 5
+	}
+	if width < 1 {
+		width = 1
+	}
+
+	perCol := float64(len(values)) / float64(width)
+	for col := 0; col < width; col++ {
+		lo := int(float64(col) * perCol)
+		hi := int(float64(col+1) * perCol)
+		if hi <= lo {
+			hi = lo + 1
+		}
+
+		var peak float64
+		for n := lo; n < hi && n < len(values); n++ {
+			if values[n] > peak {
+				peak = values[n]
+			}
+		}
+
+		idx := int(peak * float64(len(fillBlocks)-1))
+		bar += fillBlocks[idx]
+	}
+
+	fmt.Print(bar + " ")
+}
+
 type winsize struct {
 	Row    uint16
 	Col    uint16
@@ -279,11 +699,24 @@ func getTermWidthHeight() (x, y int) {
 
 func main() {
 	var (
-		printValues   *bool
-		printChnIdx   *bool
-		flagChannels  *int
-		flagBuffer    *int
-		flagAmplifier *float64
+		printValues    *bool
+		printChnIdx    *bool
+		flagChannels   *int
+		flagBuffer     *int
+		flagAmplifier  *float64
+		flagMode       *string
+		flagMeter      *string
+		flagTruePeak   *bool
+		flagBackend    *string
+		flagDevice     *string
+		flagListDevs   *bool
+		flagBallistics *string
+		flagPeakHold   *float64
+		flagPeakDecay  *float64
+		flagEmit       *string
+		flagMidi       *bool
+		flagMidiChans  *int
+		flagMidiView   *string
 	)
 
 	printValues = flag.Bool("values", false, "Print value before each channel of visualizer")
@@ -292,9 +725,80 @@ func main() {
 	flagChannels = flag.Int("channels", 2, "Amount of input channels")
 	flagBuffer = flag.Int("buffer", 10, "Smoothing graph with last n printed samples, set 1 to disable")
 	flagAmplifier = flag.Float64("amplify", 3.5, "Compensate weak audio signal with this ultimate amplifier value")
+	flagMode = flag.String("mode", "peak", "Visualizer mode, one of: peak, spectrum")
+	flagMeter = flag.String("meter", "peak", "Meter type, one of: peak, rms, lufs-m, lufs-s")
+	flagTruePeak = flag.Bool("truepeak", false, "Print an oversampled true-peak dBFS column alongside the bar")
+	flagBackend = flag.String("backend", "jack", "Audio backend, one of: jack, portaudio")
+	flagDevice = flag.String("device", "", "PortAudio input device name (backend=portaudio only, defaults to the system default)")
+	flagListDevs = flag.Bool("list-devices", false, "List PortAudio input devices and exit")
+	flagBallistics = flag.String("ballistics", dsp.BallisticsDigital, "Peak meter ballistics, one of: digital, ppm, vu")
+	flagPeakHold = flag.Float64("peak-hold", 1.5, "Seconds a peak-hold marker latches before decaying")
+	flagPeakDecay = flag.Float64("peak-decay", 20.0, "Peak-hold marker decay rate in dB/s")
+	flagEmit = flag.String("emit", "", "Stream meter values to a remote listener, e.g. ws://:8080/meter or osc://host:9000/meter")
+	flagMidi = flag.Bool("midi", false, "Visualize JACK MIDI input instead of audio")
+	flagMidiChans = flag.Int("midi-channels", 16, "Amount of MIDI input ports/rows (midi only)")
+	flagMidiView = flag.String("midi-view", "notes", "MIDI row style, one of: notes, cc (midi only)")
 	flag.Parse()
 
-	visualizer := newVisualizer(*flagChannels, *flagBuffer, *flagAmplifier, *printValues, *printChnIdx)
+	if *flagListDevs {
+		if err := listPortaudioDevices(); err != nil {
+			panic(err)
+		}
+		return
+	}
+
+	if *flagMode != "peak" && *flagMode != "spectrum" {
+		fmt.Printf("unknown mode %q, must be one of: peak, spectrum\n", *flagMode)
+		os.Exit(1)
+	}
+	switch *flagMeter {
+	case "peak", "rms", "lufs-m", "lufs-s":
+	default:
+		fmt.Printf("unknown meter %q, must be one of: peak, rms, lufs-m, lufs-s\n", *flagMeter)
+		os.Exit(1)
+	}
+	switch *flagBallistics {
+	case dsp.BallisticsDigital, dsp.BallisticsPPM, dsp.BallisticsVU:
+	default:
+		fmt.Printf("unknown ballistics %q, must be one of: digital, ppm, vu\n", *flagBallistics)
+		os.Exit(1)
+	}
+	switch *flagMidiView {
+	case "notes", "cc":
+	default:
+		fmt.Printf("unknown midi-view %q, must be one of: notes, cc\n", *flagMidiView)
+		os.Exit(1)
+	}
+
+	var backend AudioBackend
+	switch *flagBackend {
+	case "jack":
+		backend = &jackBackend{}
+	case "portaudio":
+		backend = newPortaudioBackend(*flagDevice)
+	default:
+		fmt.Printf("unknown backend %q, must be one of: jack, portaudio\n", *flagBackend)
+		os.Exit(1)
+	}
+
+	if *flagMidi {
+		if _, ok := backend.(MIDIBackend); !ok {
+			fmt.Printf("-midi requires a backend that supports MIDI input (backend=%q doesn't)\n", *flagBackend)
+			os.Exit(1)
+		}
+	}
+
+	var emitter emit.Emitter
+	if *flagEmit != "" {
+		var err error
+		emitter, err = emit.New(*flagEmit)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
+	visualizer := newVisualizer(*flagChannels, *flagBuffer, *flagAmplifier, *printValues, *printChnIdx, *flagMode, *flagMeter, *flagTruePeak, *flagBallistics, *flagPeakHold, *flagPeakDecay, emitter, *flagMidi, *flagMidiChans, *flagMidiView, backend)
 	err := visualizer.Start()
 	if err != nil {
 		panic(err)